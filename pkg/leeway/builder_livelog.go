@@ -0,0 +1,16 @@
+package leeway
+
+// LiveLog returns the tailable log stream for pkg's in-flight build, if
+// the configured Reporter maintains one. Once the package build has
+// finished its log is forgotten and none is returned. This lets
+// external code - an HTTP status server, additional reporters, tests -
+// attach to a running build's output without racing with ConsoleReporter's
+// internal writer map.
+func (b *Builder) LiveLog(pkg *Package) (*LiveLog, bool) {
+	src, ok := b.Reporter.(LiveLogSource)
+	if !ok {
+		return nil, false
+	}
+
+	return src.LiveLog(pkg)
+}
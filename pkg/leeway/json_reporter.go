@@ -0,0 +1,178 @@
+package leeway
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+)
+
+// jsonReporterSchemaVersion is bumped whenever the JSON event schema changes
+// in a way that existing consumers need to know about.
+const jsonReporterSchemaVersion = 1
+
+// JSONReporter emits one JSON object per line for every build event to a
+// configurable io.Writer, so that CI systems and IDEs can consume leeway
+// builds programmatically (see `leeway build --reporter=json`).
+//
+// Each line is independently decodable and carries a stable `schemaVersion`
+// field. JSONReporter does not buffer or reorder events: it writes them as
+// they arrive, synchronised by a single mutex so that lines from concurrent
+// package builds never interleave.
+type JSONReporter struct {
+	out io.Writer
+	mu  sync.Mutex
+
+	cached  map[*Package]bool
+	started map[*Package]time.Time
+}
+
+// NewJSONReporter produces a new JSONReporter that writes newline-delimited
+// JSON events to out.
+func NewJSONReporter(out io.Writer) *JSONReporter {
+	return &JSONReporter{
+		out:     out,
+		cached:  make(map[*Package]bool),
+		started: make(map[*Package]time.Time),
+	}
+}
+
+// jsonEvent is the envelope shared by every event line JSONReporter emits.
+type jsonEvent struct {
+	SchemaVersion int       `json:"schemaVersion"`
+	Type          string    `json:"type"`
+	Time          time.Time `json:"time"`
+
+	Package      string   `json:"package,omitempty"`
+	Version      string   `json:"version,omitempty"`
+	Dependencies []string `json:"dependencies,omitempty"`
+
+	Cached   *bool  `json:"cached,omitempty"`
+	Error    string `json:"error,omitempty"`
+	Duration string `json:"duration,omitempty"`
+
+	Stream string `json:"stream,omitempty"`
+	Data   string `json:"data,omitempty"`
+}
+
+const (
+	jsonEventBuildStarted         = "build_started"
+	jsonEventPackageBuildStarted  = "package_build_started"
+	jsonEventPackageBuildLog      = "package_build_log"
+	jsonEventPackageBuildFinished = "package_build_finished"
+	jsonEventBuildFinished        = "build_finished"
+)
+
+func (r *JSONReporter) emit(evt jsonEvent) {
+	evt.SchemaVersion = jsonReporterSchemaVersion
+	evt.Time = time.Now()
+
+	buf, err := json.Marshal(evt)
+	if err != nil {
+		// Marshalling our own struct should never fail; if it does there's
+		// nothing sensible to report to, so we drop the event rather than
+		// risk blocking or panicking on the build hotpath.
+		return
+	}
+	buf = append(buf, '\n')
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.out.Write(buf)
+}
+
+// BuildStarted is called when the build of a package is started by the user.
+func (r *JSONReporter) BuildStarted(pkg *Package, status map[*Package]PackageBuildStatus) {
+	version, err := pkg.Version()
+	if err != nil {
+		version = "unknown"
+	}
+
+	r.mu.Lock()
+	for p, s := range status {
+		r.cached[p] = s == PackageBuilt
+	}
+	r.mu.Unlock()
+
+	r.emit(jsonEvent{
+		Type:    jsonEventBuildStarted,
+		Package: pkg.FullName(),
+		Version: version,
+	})
+}
+
+// BuildFinished is called when the build of a package which was started by
+// the user has finished.
+func (r *JSONReporter) BuildFinished(pkg *Package, err error) {
+	evt := jsonEvent{
+		Type:    jsonEventBuildFinished,
+		Package: pkg.FullName(),
+	}
+	if err != nil {
+		evt.Error = err.Error()
+	}
+	r.emit(evt)
+}
+
+// PackageBuildStarted is called when a package build actually gets underway.
+func (r *JSONReporter) PackageBuildStarted(pkg *Package) {
+	version, err := pkg.Version()
+	if err != nil {
+		version = "unknown"
+	}
+
+	deps := make([]string, len(pkg.Dependencies))
+	for i, dep := range pkg.Dependencies {
+		deps[i] = dep.FullName()
+	}
+
+	r.mu.Lock()
+	r.started[pkg] = time.Now()
+	r.mu.Unlock()
+
+	r.emit(jsonEvent{
+		Type:         jsonEventPackageBuildStarted,
+		Package:      pkg.FullName(),
+		Version:      version,
+		Dependencies: deps,
+	})
+}
+
+// PackageBuildLog is called during a package build whenever a build command
+// produced some output.
+func (r *JSONReporter) PackageBuildLog(pkg *Package, isErr bool, buf []byte) {
+	stream := "stdout"
+	if isErr {
+		stream = "stderr"
+	}
+
+	r.emit(jsonEvent{
+		Type:    jsonEventPackageBuildLog,
+		Package: pkg.FullName(),
+		Stream:  stream,
+		Data:    base64.StdEncoding.EncodeToString(buf),
+	})
+}
+
+// PackageBuildFinished is called when the package build has finished.
+func (r *JSONReporter) PackageBuildFinished(pkg *Package, err error) {
+	r.mu.Lock()
+	cached := r.cached[pkg]
+	started, hasStarted := r.started[pkg]
+	delete(r.started, pkg)
+	r.mu.Unlock()
+
+	evt := jsonEvent{
+		Type:    jsonEventPackageBuildFinished,
+		Package: pkg.FullName(),
+		Cached:  &cached,
+	}
+	if hasStarted {
+		evt.Duration = time.Since(started).String()
+	}
+	if err != nil {
+		evt.Error = err.Error()
+	}
+	r.emit(evt)
+}
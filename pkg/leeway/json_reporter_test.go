@@ -0,0 +1,268 @@
+package leeway
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// newTestPackage builds a minimal *Package suitable for exercising
+// reporters - just enough for FullName(), Version() and Dependencies to
+// behave.
+func newTestPackage(name string, deps ...*Package) *Package {
+	return &Package{
+		C:            &Component{Name: "comp"},
+		Name:         name,
+		Dependencies: deps,
+	}
+}
+
+// TestJSONReporterEmitSchema checks that every emitted line is a complete,
+// independently decodable JSON object carrying the stable schemaVersion.
+func TestJSONReporterEmitSchema(t *testing.T) {
+	var buf bytes.Buffer
+	r := NewJSONReporter(&buf)
+
+	r.emit(jsonEvent{Type: jsonEventPackageBuildLog, Package: "foo:bar", Stream: "stdout", Data: "aGVsbG8="})
+
+	var got jsonEvent
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("unmarshal emitted line: %v", err)
+	}
+	if got.SchemaVersion != jsonReporterSchemaVersion {
+		t.Errorf("schemaVersion = %d, want %d", got.SchemaVersion, jsonReporterSchemaVersion)
+	}
+	if got.Type != jsonEventPackageBuildLog {
+		t.Errorf("type = %q, want %q", got.Type, jsonEventPackageBuildLog)
+	}
+	if got.Time.IsZero() {
+		t.Error("time was not stamped")
+	}
+}
+
+// TestJSONReporterEmitOrdering checks that lines emitted from a single
+// goroutine, as CompositeReporter fans events out to JSONReporter in the
+// order its own callbacks fire, are written to the underlying writer in
+// that same order and are never interleaved with one another.
+func TestJSONReporterEmitOrdering(t *testing.T) {
+	var buf bytes.Buffer
+	r := NewJSONReporter(&buf)
+
+	types := []string{
+		jsonEventBuildStarted,
+		jsonEventPackageBuildStarted,
+		jsonEventPackageBuildLog,
+		jsonEventPackageBuildFinished,
+		jsonEventBuildFinished,
+	}
+	for _, typ := range types {
+		r.emit(jsonEvent{Type: typ})
+	}
+
+	lines := strings.Split(strings.TrimRight(buf.String(), "\n"), "\n")
+	if len(lines) != len(types) {
+		t.Fatalf("got %d lines, want %d", len(lines), len(types))
+	}
+	for i, line := range lines {
+		var evt jsonEvent
+		if err := json.Unmarshal([]byte(line), &evt); err != nil {
+			t.Fatalf("line %d: unmarshal: %v", i, err)
+		}
+		if evt.Type != types[i] {
+			t.Errorf("line %d: type = %q, want %q", i, evt.Type, types[i])
+		}
+	}
+}
+
+// TestJSONReporterEmitConcurrentLinesAreWhole ensures concurrent callers -
+// as happen when multiple package builds log in parallel - never produce a
+// line that is a byte-level mix of two events.
+func TestJSONReporterEmitConcurrentLinesAreWhole(t *testing.T) {
+	var buf bytes.Buffer
+	r := NewJSONReporter(&buf)
+
+	const n = 50
+	var wg sync.WaitGroup
+	wg.Add(n)
+	for i := 0; i < n; i++ {
+		go func(i int) {
+			defer wg.Done()
+			r.emit(jsonEvent{Type: jsonEventPackageBuildLog, Package: strings.Repeat("x", i%7)})
+		}(i)
+	}
+	wg.Wait()
+
+	lines := strings.Split(strings.TrimRight(buf.String(), "\n"), "\n")
+	if len(lines) != n {
+		t.Fatalf("got %d lines, want %d", len(lines), n)
+	}
+	for i, line := range lines {
+		var evt jsonEvent
+		if err := json.Unmarshal([]byte(line), &evt); err != nil {
+			t.Fatalf("line %d is not a whole JSON object: %v", i, err)
+		}
+	}
+}
+
+// TestJSONReporterViaCompositeReporter drives a JSONReporter through the
+// real Reporter callbacks - as CompositeReporter would when fanning a
+// build out to it alongside other reporters - and checks the emitted
+// events reflect what actually happened, not just what emit() was handed
+// directly.
+func TestJSONReporterViaCompositeReporter(t *testing.T) {
+	var buf bytes.Buffer
+	jr := NewJSONReporter(&buf)
+
+	var order []string
+	recorder := &recordingReporter{record: func(name string) { order = append(order, name) }}
+
+	composite := &CompositeReporter{Children: []Reporter{jr, recorder}}
+
+	dep := newTestPackage("dep")
+	pkg := newTestPackage("app", dep)
+
+	status := map[*Package]PackageBuildStatus{pkg: PackageBuildStatus(0)}
+
+	composite.BuildStarted(pkg, status)
+	composite.PackageBuildStarted(pkg)
+	composite.PackageBuildLog(pkg, false, []byte("building...\n"))
+	composite.PackageBuildLog(pkg, true, []byte("a warning\n"))
+	composite.PackageBuildFinished(pkg, nil)
+	composite.BuildFinished(pkg, nil)
+
+	wantTypes := []string{
+		jsonEventBuildStarted,
+		jsonEventPackageBuildStarted,
+		jsonEventPackageBuildLog,
+		jsonEventPackageBuildLog,
+		jsonEventPackageBuildFinished,
+		jsonEventBuildFinished,
+	}
+	wantOrder := []string{
+		"BuildStarted", "PackageBuildStarted", "PackageBuildLog", "PackageBuildLog",
+		"PackageBuildFinished", "BuildFinished",
+	}
+	if len(order) != len(wantOrder) {
+		t.Fatalf("recorder saw %d calls, want %d", len(order), len(wantOrder))
+	}
+	for i := range wantOrder {
+		if order[i] != wantOrder[i] {
+			t.Errorf("composite call %d = %q, want %q", i, order[i], wantOrder[i])
+		}
+	}
+
+	lines := strings.Split(strings.TrimRight(buf.String(), "\n"), "\n")
+	if len(lines) != len(wantTypes) {
+		t.Fatalf("got %d JSON lines, want %d", len(lines), len(wantTypes))
+	}
+
+	events := make([]jsonEvent, len(lines))
+	for i, line := range lines {
+		if err := json.Unmarshal([]byte(line), &events[i]); err != nil {
+			t.Fatalf("line %d: unmarshal: %v", i, err)
+		}
+		if events[i].Type != wantTypes[i] {
+			t.Errorf("line %d: type = %q, want %q", i, events[i].Type, wantTypes[i])
+		}
+	}
+
+	started := events[1]
+	if started.Package != pkg.FullName() {
+		t.Errorf("package_build_started: package = %q, want %q", started.Package, pkg.FullName())
+	}
+	if len(started.Dependencies) != 1 || started.Dependencies[0] != dep.FullName() {
+		t.Errorf("package_build_started: dependencies = %v, want [%q]", started.Dependencies, dep.FullName())
+	}
+
+	stdoutLog := events[2]
+	if stdoutLog.Stream != "stdout" {
+		t.Errorf("first log event: stream = %q, want stdout", stdoutLog.Stream)
+	}
+	if got, _ := base64.StdEncoding.DecodeString(stdoutLog.Data); string(got) != "building...\n" {
+		t.Errorf("first log event: data decodes to %q, want %q", got, "building...\n")
+	}
+
+	stderrLog := events[3]
+	if stderrLog.Stream != "stderr" {
+		t.Errorf("second log event: stream = %q, want stderr", stderrLog.Stream)
+	}
+
+	finished := events[4]
+	if finished.Cached == nil || *finished.Cached {
+		t.Errorf("package_build_finished: cached = %v, want false (package was not pre-cached)", finished.Cached)
+	}
+	if finished.Duration == "" {
+		t.Error("package_build_finished: duration was not set")
+	}
+	if _, err := time.ParseDuration(finished.Duration); err != nil {
+		t.Errorf("package_build_finished: duration %q does not parse: %v", finished.Duration, err)
+	}
+}
+
+// TestJSONReporterCachedBookkeeping checks that PackageBuildFinished reports
+// cached=true for a package the BuildStarted status map marked as already
+// built, and that a failed build's error message is carried through.
+func TestJSONReporterCachedBookkeeping(t *testing.T) {
+	var buf bytes.Buffer
+	jr := NewJSONReporter(&buf)
+
+	cached := newTestPackage("cached-pkg")
+	built := newTestPackage("built-pkg")
+
+	jr.BuildStarted(cached, map[*Package]PackageBuildStatus{
+		cached: PackageBuilt,
+		built:  PackageBuildStatus(0),
+	})
+
+	jr.PackageBuildFinished(cached, nil)
+	jr.PackageBuildFinished(built, errors.New("compile failed"))
+
+	// BuildStarted itself emits a build_started line before the two
+	// package_build_finished lines we're asserting on.
+	lines := strings.Split(strings.TrimRight(buf.String(), "\n"), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("got %d lines, want 3", len(lines))
+	}
+
+	var cachedEvt, builtEvt jsonEvent
+	if err := json.Unmarshal([]byte(lines[1]), &cachedEvt); err != nil {
+		t.Fatalf("unmarshal cached event: %v", err)
+	}
+	if err := json.Unmarshal([]byte(lines[2]), &builtEvt); err != nil {
+		t.Fatalf("unmarshal built event: %v", err)
+	}
+
+	if cachedEvt.Cached == nil || !*cachedEvt.Cached {
+		t.Errorf("cached package: cached = %v, want true", cachedEvt.Cached)
+	}
+	if builtEvt.Cached == nil || *builtEvt.Cached {
+		t.Errorf("built package: cached = %v, want false", builtEvt.Cached)
+	}
+	if builtEvt.Error != "compile failed" {
+		t.Errorf("built package: error = %q, want %q", builtEvt.Error, "compile failed")
+	}
+}
+
+// recordingReporter is a no-op Reporter that records which callback was
+// invoked, in order, so tests can assert on CompositeReporter's fan-out
+// sequencing without depending on a second real reporter's side effects.
+type recordingReporter struct {
+	record func(name string)
+}
+
+func (r *recordingReporter) BuildStarted(pkg *Package, status map[*Package]PackageBuildStatus) {
+	r.record("BuildStarted")
+}
+func (r *recordingReporter) BuildFinished(pkg *Package, err error) { r.record("BuildFinished") }
+func (r *recordingReporter) PackageBuildStarted(pkg *Package)      { r.record("PackageBuildStarted") }
+func (r *recordingReporter) PackageBuildLog(pkg *Package, isErr bool, buf []byte) {
+	r.record("PackageBuildLog")
+}
+func (r *recordingReporter) PackageBuildFinished(pkg *Package, err error) {
+	r.record("PackageBuildFinished")
+}
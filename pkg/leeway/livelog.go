@@ -0,0 +1,192 @@
+package leeway
+
+import (
+	"context"
+	"io"
+	"sync"
+)
+
+// DefaultLiveLogBacklog is the default number of bytes a LiveLog keeps
+// around so that a tailer attaching mid-build can replay recent output
+// before it starts streaming live.
+const DefaultLiveLogBacklog = 64 * 1024
+
+// LiveLog is a tailable, append-only log stream for a single in-flight
+// package build, modeled after LUCI's livelog. A build command's output is
+// appended via Write; any number of readers can concurrently Tail the
+// stream, each one first replaying the buffered backlog and then receiving
+// everything written afterwards. Close unblocks all readers with io.EOF
+// once the package build has finished.
+//
+// LiveLog's zero value is not usable - construct one with NewLiveLog.
+type LiveLog struct {
+	mu      sync.Mutex
+	cond    *sync.Cond
+	backlog []byte
+	// offset is how many bytes have ever been evicted from the front of
+	// backlog, i.e. the absolute stream position of backlog[0].
+	offset     int
+	backlogCap int
+	closed     bool
+}
+
+// NewLiveLog creates a LiveLog that keeps at most backlogCap bytes of
+// backlog for replay. A backlogCap <= 0 uses DefaultLiveLogBacklog.
+func NewLiveLog(backlogCap int) *LiveLog {
+	if backlogCap <= 0 {
+		backlogCap = DefaultLiveLogBacklog
+	}
+
+	l := &LiveLog{backlogCap: backlogCap}
+	l.cond = sync.NewCond(&l.mu)
+	return l
+}
+
+// Write appends buf to the log and wakes any tailers blocked waiting for
+// more data. Write never blocks on readers, so a slow or absent tailer can
+// never stall the build that's producing this output.
+func (l *LiveLog) Write(buf []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.closed {
+		return 0, io.ErrClosedPipe
+	}
+
+	l.backlog = append(l.backlog, buf...)
+	if over := len(l.backlog) - l.backlogCap; over > 0 {
+		l.offset += over
+		l.backlog = l.backlog[over:]
+	}
+	l.cond.Broadcast()
+
+	return len(buf), nil
+}
+
+// Close marks the log as finished. Every reader currently blocked in Read,
+// or that calls Read afterwards, receives io.EOF once it has drained
+// whatever was written before Close.
+func (l *LiveLog) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.closed = true
+	l.cond.Broadcast()
+
+	return nil
+}
+
+// Tail returns an io.Reader that replays the buffered backlog and then
+// streams everything written to the log afterwards, until the log is
+// closed (io.EOF) or ctx is done (ctx.Err()).
+func (l *LiveLog) Tail(ctx context.Context) io.Reader {
+	t := &liveLogTailer{log: l, ctx: ctx}
+
+	l.mu.Lock()
+	t.pos = l.offset
+	l.mu.Unlock()
+
+	if ctx != nil {
+		go func() {
+			<-ctx.Done()
+			l.mu.Lock()
+			l.cond.Broadcast()
+			l.mu.Unlock()
+		}()
+	}
+
+	return t
+}
+
+// liveLogTailer tracks one reader's position in a LiveLog's logical byte
+// stream.
+type liveLogTailer struct {
+	log *LiveLog
+	ctx context.Context
+	pos int
+}
+
+func (t *liveLogTailer) Read(p []byte) (int, error) {
+	l := t.log
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for {
+		rel := t.pos - l.offset
+		if rel < 0 {
+			// We fell behind the backlog window - the reader missed
+			// output that's since been evicted. Skip ahead rather than
+			// replaying stale data at the wrong offset.
+			rel = 0
+			t.pos = l.offset
+		}
+
+		if rel < len(l.backlog) {
+			n := copy(p, l.backlog[rel:])
+			t.pos += n
+			return n, nil
+		}
+
+		if l.closed {
+			return 0, io.EOF
+		}
+
+		if t.ctx != nil && t.ctx.Err() != nil {
+			return 0, t.ctx.Err()
+		}
+
+		l.cond.Wait()
+	}
+}
+
+// LiveLogSource is implemented by reporters that maintain a per-package
+// LiveLog, so that a Builder can let external code attach to a running
+// build's output regardless of which Reporter happens to be configured.
+type LiveLogSource interface {
+	// LiveLog returns the tailable log stream for pkg's current build, and
+	// whether one exists.
+	LiveLog(pkg *Package) (*LiveLog, bool)
+}
+
+// liveLogRegistry tracks one LiveLog per in-flight package build. It's safe
+// for concurrent use by the goroutine driving the build and any number of
+// tailers.
+type liveLogRegistry struct {
+	mu   sync.Mutex
+	logs map[string]*LiveLog
+}
+
+func newLiveLogRegistry() *liveLogRegistry {
+	return &liveLogRegistry{logs: make(map[string]*LiveLog)}
+}
+
+// open creates (or replaces) the LiveLog for name.
+func (s *liveLogRegistry) open(name string) *LiveLog {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	l := NewLiveLog(DefaultLiveLogBacklog)
+	s.logs[name] = l
+	return l
+}
+
+func (s *liveLogRegistry) get(name string) (*LiveLog, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	l, ok := s.logs[name]
+	return l, ok
+}
+
+// close closes and forgets the LiveLog for name, if any.
+func (s *liveLogRegistry) close(name string) {
+	s.mu.Lock()
+	l, ok := s.logs[name]
+	delete(s.logs, name)
+	s.mu.Unlock()
+
+	if ok {
+		l.Close()
+	}
+}
@@ -0,0 +1,119 @@
+package leeway
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+)
+
+// TestLiveLogTailReplaysBacklog checks that a tailer attaching after some
+// output has already been written replays that backlog before anything new
+// is written.
+func TestLiveLogTailReplaysBacklog(t *testing.T) {
+	l := NewLiveLog(DefaultLiveLogBacklog)
+
+	if _, err := l.Write([]byte("hello ")); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if _, err := l.Write([]byte("world")); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+
+	tail := l.Tail(context.Background())
+
+	buf := make([]byte, 64)
+	n, err := tail.Read(buf)
+	if err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	if got := string(buf[:n]); got != "hello world" {
+		t.Errorf("replayed backlog = %q, want %q", got, "hello world")
+	}
+}
+
+// TestLiveLogTailEvictsAndSkipsForward checks that once the backlog ring has
+// evicted bytes a tailer never saw, Read skips the tailer's position forward
+// to the start of what's still buffered instead of replaying from a stale
+// offset.
+func TestLiveLogTailEvictsAndSkipsForward(t *testing.T) {
+	const backlogCap = 8
+	l := NewLiveLog(backlogCap)
+
+	// A tailer that attaches before anything is written starts at
+	// position 0, but bytes evicted before it reads are lost to it.
+	earlyTail := l.Tail(context.Background())
+
+	if _, err := l.Write([]byte("0123456789")); err != nil { // 10 bytes > 8 byte cap
+		t.Fatalf("Write: %v", err)
+	}
+
+	// A tailer attaching only now starts at the current offset - it must
+	// never see bytes already evicted from the backlog.
+	lateTail := l.Tail(context.Background())
+
+	buf := make([]byte, 64)
+
+	n, err := lateTail.Read(buf)
+	if err != nil {
+		t.Fatalf("late tailer Read: %v", err)
+	}
+	if got := string(buf[:n]); got != "23456789" {
+		t.Errorf("late tailer replay = %q, want last %d bytes %q", got, backlogCap, "23456789")
+	}
+
+	// The early tailer's position (0) is now behind the evicted window
+	// (offset 2); Read must skip it forward rather than returning stale
+	// or out-of-bounds data.
+	n, err = earlyTail.Read(buf)
+	if err != nil {
+		t.Fatalf("early tailer Read: %v", err)
+	}
+	if got := string(buf[:n]); got != "23456789" {
+		t.Errorf("early tailer replay after eviction = %q, want %q", got, "23456789")
+	}
+}
+
+// TestLiveLogCloseUnblocksReader checks that a reader blocked in Read,
+// waiting for more data, is woken with io.EOF once Close is called.
+func TestLiveLogCloseUnblocksReader(t *testing.T) {
+	l := NewLiveLog(DefaultLiveLogBacklog)
+	tail := l.Tail(context.Background())
+
+	result := make(chan error, 1)
+	go func() {
+		buf := make([]byte, 16)
+		_, err := tail.Read(buf)
+		result <- err
+	}()
+
+	// Give the reader a moment to actually block in cond.Wait before we
+	// close, so this test would fail if Close didn't wake it.
+	time.Sleep(20 * time.Millisecond)
+
+	if err := l.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	select {
+	case err := <-result:
+		if err != io.EOF {
+			t.Errorf("Read after Close returned %v, want io.EOF", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Read did not return after Close")
+	}
+}
+
+// TestLiveLogWriteAfterCloseErrors checks that Write refuses new data once
+// the log has been closed, rather than silently accepting it.
+func TestLiveLogWriteAfterCloseErrors(t *testing.T) {
+	l := NewLiveLog(DefaultLiveLogBacklog)
+	if err := l.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	if _, err := l.Write([]byte("too late")); err != io.ErrClosedPipe {
+		t.Errorf("Write after Close = %v, want io.ErrClosedPipe", err)
+	}
+}
@@ -46,15 +46,27 @@ type Reporter interface {
 type ConsoleReporter struct {
 	writer map[string]io.Writer
 	mu     sync.RWMutex
+
+	live *liveLogRegistry
 }
 
 // NewConsoleReporter produces a new console logger
 func NewConsoleReporter() *ConsoleReporter {
 	return &ConsoleReporter{
 		writer: make(map[string]io.Writer),
+		live:   newLiveLogRegistry(),
 	}
 }
 
+// LiveLog returns the tailable log stream backing pkg's current build
+// output, if any. It shares its underlying buffer with whatever
+// ConsoleReporter itself is writing to stdout, so attaching a tailer
+// mid-build replays the same buffered prefix before streaming live -
+// without racing with the writer map above.
+func (r *ConsoleReporter) LiveLog(pkg *Package) (*LiveLog, bool) {
+	return r.live.get(pkg.FullName())
+}
+
 // BuildStarted is called when the build of a package is started by the user.
 func (r *ConsoleReporter) BuildStarted(pkg *Package, status map[*Package]PackageBuildStatus) {
 	// now that the local cache is warm, we can print the list of work we have to do
@@ -94,11 +106,7 @@ func (r *ConsoleReporter) BuildFinished(pkg *Package, err error) {
 func (r *ConsoleReporter) PackageBuildStarted(pkg *Package) {
 	nme := pkg.FullName()
 
-	out := textio.NewPrefixWriter(os.Stdout, getRunPrefix(pkg))
-
-	r.mu.Lock()
-	r.writer[nme] = out
-	r.mu.Unlock()
+	out := r.openWriter(nme, pkg)
 
 	version, err := pkg.Version()
 	if err != nil {
@@ -116,11 +124,8 @@ func (r *ConsoleReporter) PackageBuildLog(pkg *Package, isErr bool, buf []byte)
 	out, ok := r.writer[nme]
 	r.mu.RUnlock()
 	if !ok {
-		r.mu.Lock()
-		out = textio.NewPrefixWriter(os.Stdout, getRunPrefix(pkg))
-		r.writer[nme] = out
 		log.WithField("package", nme).Debug("saw build log output before the build started")
-		r.mu.Unlock()
+		out = r.openWriter(nme, pkg)
 	}
 
 	out.Write(buf)
@@ -139,12 +144,31 @@ func (r *ConsoleReporter) PackageBuildFinished(pkg *Package, err error) {
 	r.mu.RUnlock()
 
 	if !ok {
-		out = textio.NewPrefixWriter(os.Stdout, getRunPrefix(pkg))
+		out = r.openWriter(nme, pkg)
 	}
 
 	io.WriteString(out, msg)
 
+	r.mu.Lock()
 	delete(r.writer, nme)
+	r.mu.Unlock()
+	r.live.close(nme)
+}
+
+// openWriter sets up the combined stdout-prefix-writer and LiveLog for a
+// package so that both see exactly the same bytes: a tailer attaching
+// mid-build replays the same backlog that's already scrolled past on the
+// terminal, then streams whatever comes next.
+func (r *ConsoleReporter) openWriter(nme string, pkg *Package) io.Writer {
+	prefixed := textio.NewPrefixWriter(os.Stdout, getRunPrefix(pkg))
+	live := r.live.open(nme)
+	out := io.MultiWriter(prefixed, live)
+
+	r.mu.Lock()
+	r.writer[nme] = out
+	r.mu.Unlock()
+
+	return out
 }
 
 func getRunPrefix(p *Package) string {
@@ -190,3 +214,18 @@ func (c *CompositeReporter) PackageBuildFinished(pkg *Package, err error) {
 		r.PackageBuildFinished(pkg, err)
 	}
 }
+
+// LiveLog returns the tailable log stream for pkg from the first child
+// that maintains one, so that combining e.g. ConsoleReporter with
+// JSONReporter or WebhookReporter in a CompositeReporter doesn't defeat
+// LiveLogSource for callers like Builder.LiveLog.
+func (c *CompositeReporter) LiveLog(pkg *Package) (*LiveLog, bool) {
+	for _, r := range c.Children {
+		if src, ok := r.(LiveLogSource); ok {
+			if live, ok := src.LiveLog(pkg); ok {
+				return live, true
+			}
+		}
+	}
+	return nil, false
+}
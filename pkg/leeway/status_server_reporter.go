@@ -0,0 +1,266 @@
+package leeway
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// StatusServerReporter runs an embedded HTTP server exposing the state of
+// the current build: `GET /api/status` for the per-package DAG state, `GET
+// /api/packages/{name}/log` for a live Server-Sent Events log stream
+// backed by the LiveLog subsystem, and a minimal HTML dashboard at `/`.
+//
+// It's the leeway analog of the status pages other Go build coordinators
+// expose, but meant to run locally for a developer tailing a long
+// multi-package build from a browser instead of a terminal.
+type StatusServerReporter struct {
+	addr string
+	srv  *http.Server
+
+	live *liveLogRegistry
+
+	mu       sync.RWMutex
+	packages map[string]*statusServerPackage
+}
+
+type statusServerPackage struct {
+	Name      string    `json:"name"`
+	Version   string    `json:"version"`
+	State     string    `json:"state"`
+	StartedAt time.Time `json:"startedAt,omitempty"`
+	EndedAt   time.Time `json:"endedAt,omitempty"`
+	Error     string    `json:"error,omitempty"`
+}
+
+// NewStatusServerReporter produces a StatusServerReporter listening on
+// addr (e.g. "localhost:8080"). The server is started in the background by
+// the first BuildStarted call and stopped on BuildFinished.
+func NewStatusServerReporter(addr string) *StatusServerReporter {
+	r := &StatusServerReporter{
+		addr:     addr,
+		live:     newLiveLogRegistry(),
+		packages: make(map[string]*statusServerPackage),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/status", r.handleStatus)
+	mux.HandleFunc("/api/packages/", r.handlePackageLog)
+	mux.HandleFunc("/", r.handleIndex)
+	r.srv = &http.Server{Addr: addr, Handler: mux}
+
+	return r
+}
+
+// LiveLog returns the tailable log stream for pkg's current build, if any.
+// It satisfies LiveLogSource so that Builder.LiveLog reaches this reporter
+// the same way it would reach ConsoleReporter.
+func (r *StatusServerReporter) LiveLog(pkg *Package) (*LiveLog, bool) {
+	return r.live.get(pkg.FullName())
+}
+
+// BuildStarted is called when the build of a package is started by the user.
+func (r *StatusServerReporter) BuildStarted(pkg *Package, status map[*Package]PackageBuildStatus) {
+	r.mu.Lock()
+	for p, s := range status {
+		version, err := p.Version()
+		if err != nil {
+			version = "unknown"
+		}
+
+		state := "queued"
+		if s == PackageBuilt {
+			state = "cached"
+		}
+
+		r.packages[p.FullName()] = &statusServerPackage{
+			Name:    p.FullName(),
+			Version: version,
+			State:   state,
+		}
+	}
+	r.mu.Unlock()
+
+	go func() {
+		if err := r.srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.WithError(err).Error("status server reporter: HTTP server failed")
+		}
+	}()
+}
+
+// BuildFinished is called when the build of a package which was started by
+// the user has finished.
+func (r *StatusServerReporter) BuildFinished(pkg *Package, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if shutdownErr := r.srv.Shutdown(ctx); shutdownErr != nil {
+		log.WithError(shutdownErr).Warn("status server reporter: error shutting down HTTP server")
+	}
+}
+
+// PackageBuildStarted is called when a package build actually gets underway.
+func (r *StatusServerReporter) PackageBuildStarted(pkg *Package) {
+	nme := pkg.FullName()
+
+	r.mu.Lock()
+	p, ok := r.packages[nme]
+	if !ok {
+		p = &statusServerPackage{Name: nme}
+		r.packages[nme] = p
+	}
+	p.State = "building"
+	p.StartedAt = time.Now()
+	r.mu.Unlock()
+
+	r.live.open(nme)
+}
+
+// PackageBuildLog is called during a package build whenever a build command produced some output.
+func (r *StatusServerReporter) PackageBuildLog(pkg *Package, isErr bool, buf []byte) {
+	nme := pkg.FullName()
+
+	live, ok := r.live.get(nme)
+	if !ok {
+		log.WithField("package", nme).Debug("saw build log output before the build started")
+		live = r.live.open(nme)
+	}
+
+	live.Write(buf)
+}
+
+// PackageBuildFinished is called when the package build has finished.
+func (r *StatusServerReporter) PackageBuildFinished(pkg *Package, err error) {
+	nme := pkg.FullName()
+
+	r.mu.Lock()
+	p, ok := r.packages[nme]
+	if !ok {
+		p = &statusServerPackage{Name: nme}
+		r.packages[nme] = p
+	}
+	p.EndedAt = time.Now()
+	if err != nil {
+		p.State = "failed"
+		p.Error = err.Error()
+	} else {
+		p.State = "done"
+	}
+	r.mu.Unlock()
+
+	r.live.close(nme)
+}
+
+func (r *StatusServerReporter) handleStatus(w http.ResponseWriter, req *http.Request) {
+	r.mu.RLock()
+	pkgs := make([]*statusServerPackage, 0, len(r.packages))
+	for _, p := range r.packages {
+		// Snapshot the value, not the pointer: p is mutated in place by the
+		// build hotpath under r.mu, so encoding through the live pointer
+		// after releasing the lock would race with those writes.
+		snapshot := *p
+		pkgs = append(pkgs, &snapshot)
+	}
+	r.mu.RUnlock()
+
+	sort.Slice(pkgs, func(i, j int) bool { return pkgs[i].Name < pkgs[j].Name })
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(pkgs)
+}
+
+// handlePackageLog streams a package's live log as Server-Sent Events by
+// subscribing to its LiveLog.
+func (r *StatusServerReporter) handlePackageLog(w http.ResponseWriter, req *http.Request) {
+	nme := strings.TrimPrefix(req.URL.Path, "/api/packages/")
+	nme = strings.TrimSuffix(nme, "/log")
+
+	r.mu.RLock()
+	_, known := r.packages[nme]
+	r.mu.RUnlock()
+	if !known {
+		http.NotFound(w, req)
+		return
+	}
+
+	live, ok := r.live.get(nme)
+	if !ok {
+		http.Error(w, "no live log for this package yet", http.StatusNotFound)
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+
+	tail := live.Tail(req.Context())
+	buf := make([]byte, 4096)
+	for {
+		n, err := tail.Read(buf)
+		if n > 0 {
+			// Every line of an SSE event needs its own "data:" prefix;
+			// lines without one are not part of the event's data.
+			for _, line := range strings.Split(strings.TrimRight(string(buf[:n]), "\n"), "\n") {
+				fmt.Fprintf(w, "data: %s\n", line)
+			}
+			fmt.Fprint(w, "\n")
+			flusher.Flush()
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+func (r *StatusServerReporter) handleIndex(w http.ResponseWriter, req *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	fmt.Fprint(w, statusServerIndexHTML)
+}
+
+const statusServerIndexHTML = `<!DOCTYPE html>
+<html>
+<head>
+  <meta charset="utf-8">
+  <title>leeway build status</title>
+  <style>
+    body { font-family: sans-serif; margin: 2rem; }
+    table { border-collapse: collapse; width: 100%; }
+    th, td { text-align: left; padding: 0.25rem 0.75rem; border-bottom: 1px solid #ddd; }
+    .state-failed { color: #b00020; }
+    .state-done, .state-cached { color: #2e7d32; }
+    .state-building { color: #b36b00; }
+  </style>
+</head>
+<body>
+  <h1>leeway build status</h1>
+  <table id="packages"><thead><tr><th>Package</th><th>Version</th><th>State</th></tr></thead><tbody></tbody></table>
+  <script>
+    async function refresh() {
+      const res = await fetch('/api/status');
+      const pkgs = await res.json();
+      const body = document.querySelector('#packages tbody');
+      body.innerHTML = '';
+      for (const p of pkgs) {
+        const row = document.createElement('tr');
+        row.innerHTML = '<td>' + p.name + '</td><td>' + p.version + '</td><td class="state-' + p.state + '">' + p.state + '</td>';
+        body.appendChild(row);
+      }
+    }
+    refresh();
+    setInterval(refresh, 2000);
+  </script>
+</body>
+</html>
+`
@@ -0,0 +1,494 @@
+package leeway
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/term"
+)
+
+const (
+	// tuiLogBacklogSize bounds how many log lines we keep per package so that
+	// switching focus can redraw the backlog without unbounded memory growth
+	// over the course of a long multi-package build.
+	tuiLogBacklogSize = 2000
+
+	// tuiMsgQueueSize bounds the number of build events buffered between the
+	// build hotpath and the Bubble Tea program's own goroutine.
+	tuiMsgQueueSize = 4096
+)
+
+// TUIReporter renders build progress as an interactive terminal UI built on
+// Bubble Tea: a table of all packages with their build state and elapsed
+// time, plus a tail of the currently focused package's log output.
+//
+// Key bindings: j/k move focus down and up the package list, / starts a
+// filter on the package name, esc/enter leave the filter, q or ctrl+c quits.
+//
+// Reporter callbacks run on the build's hotpath and must never block, so
+// TUIReporter only ever enqueues messages for its own goroutine to consume.
+// Under sustained backpressure, messages of any kind (not only log output)
+// are dropped rather than stalling the build - the ring buffer is a live
+// view, not a log archive.
+type TUIReporter struct {
+	msgs    chan tea.Msg
+	program *tea.Program
+	done    chan struct{}
+
+	// stopped is closed exactly once, when the build has actually
+	// finished. Until then, producers on the build hotpath may call send
+	// at any time - including after the user has quit the view with q -
+	// so send must never touch a channel that could already be closed.
+	stopped  chan struct{}
+	stopOnce sync.Once
+}
+
+// NewTUIReporter produces a Reporter that renders an interactive TUI. If
+// stdout is not a terminal there is nothing to render onto, so it falls
+// back to a *ConsoleReporter transparently.
+func NewTUIReporter() Reporter {
+	if !term.IsTerminal(int(os.Stdout.Fd())) {
+		return NewConsoleReporter()
+	}
+
+	r := &TUIReporter{
+		msgs:    make(chan tea.Msg, tuiMsgQueueSize),
+		done:    make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+	r.program = tea.NewProgram(newTUIModel(), tea.WithAltScreen())
+
+	go r.run()
+
+	return r
+}
+
+func (r *TUIReporter) run() {
+	defer close(r.done)
+
+	go func() {
+		for {
+			select {
+			case msg := <-r.msgs:
+				// Program.Send is documented as safe to call even after
+				// the program has quit - e.g. because the user pressed q
+				// while the build was still running - so it's fine for
+				// this to keep forwarding right up until stopped closes.
+				r.program.Send(msg)
+			case <-r.stopped:
+				return
+			}
+		}
+	}()
+
+	// Run restores terminal state on return - including when the model
+	// panics - so a crashing Update can never leave the user's terminal
+	// stuck in raw/alt-screen mode.
+	if _, err := r.program.Run(); err != nil {
+		log.WithError(err).Error("TUI reporter exited with an error")
+	}
+}
+
+// send enqueues msg without blocking the build hotpath. Under backpressure -
+// the UI goroutine falling behind - messages are dropped rather than
+// stalling the caller. r.msgs is never closed, so send stays safe to call
+// for as long as the build is running, regardless of whether the user has
+// already quit the view.
+func (r *TUIReporter) send(msg tea.Msg) {
+	select {
+	case r.msgs <- msg:
+	case <-r.stopped:
+	default:
+		log.WithField("msg", fmt.Sprintf("%T", msg)).Debug("TUI reporter dropped a message under backpressure")
+	}
+}
+
+// stop marks the reporter as finished, letting the forwarding goroutine in
+// run exit. Safe to call more than once or concurrently.
+func (r *TUIReporter) stop() {
+	r.stopOnce.Do(func() { close(r.stopped) })
+}
+
+// BuildStarted is called when the build of a package is started by the user.
+func (r *TUIReporter) BuildStarted(pkg *Package, status map[*Package]PackageBuildStatus) {
+	r.send(tuiBuildStartedMsg{pkg: pkg, status: status})
+}
+
+// BuildFinished is called when the build of a package which was started by
+// the user has finished.
+func (r *TUIReporter) BuildFinished(pkg *Package, err error) {
+	r.send(tuiBuildFinishedMsg{pkg: pkg, err: err})
+	r.program.Quit()
+	<-r.done
+	r.stop()
+}
+
+// PackageBuildStarted is called when a package build actually gets underway.
+func (r *TUIReporter) PackageBuildStarted(pkg *Package) {
+	r.send(tuiPackageStartedMsg{pkg: pkg})
+}
+
+// PackageBuildLog is called during a package build whenever a build command
+// produced some output.
+func (r *TUIReporter) PackageBuildLog(pkg *Package, isErr bool, buf []byte) {
+	cp := make([]byte, len(buf))
+	copy(cp, buf)
+	r.send(tuiPackageLogMsg{pkg: pkg, isErr: isErr, buf: cp})
+}
+
+// PackageBuildFinished is called when the package build has finished.
+func (r *TUIReporter) PackageBuildFinished(pkg *Package, err error) {
+	r.send(tuiPackageFinishedMsg{pkg: pkg, err: err})
+}
+
+type tuiBuildStartedMsg struct {
+	pkg    *Package
+	status map[*Package]PackageBuildStatus
+}
+
+type tuiBuildFinishedMsg struct {
+	pkg *Package
+	err error
+}
+
+type tuiPackageStartedMsg struct {
+	pkg *Package
+}
+
+type tuiPackageLogMsg struct {
+	pkg   *Package
+	isErr bool
+	buf   []byte
+}
+
+type tuiPackageFinishedMsg struct {
+	pkg *Package
+	err error
+}
+
+type tuiTickMsg time.Time
+
+// tuiRingBuffer is a fixed-capacity ring of log lines. It lets a package's
+// focused log pane redraw its backlog instantly on focus switch without
+// keeping the entire build log in memory.
+type tuiRingBuffer struct {
+	mu    sync.Mutex
+	lines []string
+	start int
+	size  int
+}
+
+func newTUIRingBuffer(capacity int) *tuiRingBuffer {
+	return &tuiRingBuffer{lines: make([]string, capacity)}
+}
+
+func (b *tuiRingBuffer) push(line string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	capacity := len(b.lines)
+	idx := (b.start + b.size) % capacity
+	b.lines[idx] = line
+	if b.size < capacity {
+		b.size++
+	} else {
+		b.start = (b.start + 1) % capacity
+	}
+}
+
+func (b *tuiRingBuffer) tail(n int) []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if n > b.size {
+		n = b.size
+	}
+	out := make([]string, n)
+	capacity := len(b.lines)
+	for i := 0; i < n; i++ {
+		out[i] = b.lines[(b.start+b.size-n+i)%capacity]
+	}
+	return out
+}
+
+type tuiPackageState int
+
+const (
+	tuiStateQueued tuiPackageState = iota
+	tuiStateCached
+	tuiStateBuilding
+	tuiStateDone
+	tuiStateFailed
+)
+
+func (s tuiPackageState) String() string {
+	switch s {
+	case tuiStateCached:
+		return "cached"
+	case tuiStateBuilding:
+		return "building"
+	case tuiStateDone:
+		return "done"
+	case tuiStateFailed:
+		return "failed"
+	default:
+		return "queued"
+	}
+}
+
+type tuiPackageRow struct {
+	name     string
+	version  string
+	state    tuiPackageState
+	started  time.Time
+	finished time.Time
+	logs     *tuiRingBuffer
+}
+
+func (row *tuiPackageRow) elapsed() string {
+	if row.started.IsZero() {
+		return "-"
+	}
+	end := row.finished
+	if end.IsZero() {
+		end = time.Now()
+	}
+	return end.Sub(row.started).Round(time.Second).String()
+}
+
+var (
+	tuiHeaderStyle   = lipgloss.NewStyle().Bold(true).Underline(true)
+	tuiCachedStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("10"))
+	tuiBuildingStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("11"))
+	tuiDoneStyle     = lipgloss.NewStyle().Foreground(lipgloss.Color("10"))
+	tuiFailedStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("9"))
+	tuiFocusStyle    = lipgloss.NewStyle().Reverse(true)
+	tuiLogPaneStyle  = lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).Padding(0, 1)
+)
+
+// tuiModel is the Bubble Tea model driving the TUIReporter's display. All
+// mutation happens inside Update, which runs on the Bubble Tea program's own
+// goroutine, so it needs no locking of its own.
+type tuiModel struct {
+	order     []string
+	rows      map[string]*tuiPackageRow
+	focus     string
+	filtering bool
+	filter    string
+	width     int
+	height    int
+	quitting  bool
+}
+
+func newTUIModel() *tuiModel {
+	return &tuiModel{rows: make(map[string]*tuiPackageRow)}
+}
+
+func (m *tuiModel) Init() tea.Cmd {
+	return tuiTick()
+}
+
+func tuiTick() tea.Cmd {
+	return tea.Tick(time.Second, func(t time.Time) tea.Msg { return tuiTickMsg(t) })
+}
+
+func (m *tuiModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+
+	case tea.KeyMsg:
+		return m.handleKey(msg)
+
+	case tuiTickMsg:
+		return m, tuiTick()
+
+	case tuiBuildStartedMsg:
+		m.onBuildStarted(msg)
+
+	case tuiPackageStartedMsg:
+		if row, ok := m.rows[msg.pkg.FullName()]; ok {
+			row.state = tuiStateBuilding
+			row.started = time.Now()
+		}
+
+	case tuiPackageLogMsg:
+		if row, ok := m.rows[msg.pkg.FullName()]; ok {
+			for _, line := range strings.Split(strings.TrimRight(string(msg.buf), "\n"), "\n") {
+				row.logs.push(line)
+			}
+		}
+
+	case tuiPackageFinishedMsg:
+		if row, ok := m.rows[msg.pkg.FullName()]; ok {
+			row.finished = time.Now()
+			if msg.err != nil {
+				row.state = tuiStateFailed
+			} else {
+				row.state = tuiStateDone
+			}
+		}
+
+	case tuiBuildFinishedMsg:
+		m.quitting = true
+		return m, tea.Quit
+	}
+
+	return m, nil
+}
+
+func (m *tuiModel) onBuildStarted(msg tuiBuildStartedMsg) {
+	for pkg, status := range msg.status {
+		name := pkg.FullName()
+		version, err := pkg.Version()
+		if err != nil {
+			version = "unknown"
+		}
+
+		state := tuiStateQueued
+		if status == PackageBuilt {
+			state = tuiStateCached
+		}
+
+		m.rows[name] = &tuiPackageRow{
+			name:    name,
+			version: version,
+			state:   state,
+			logs:    newTUIRingBuffer(tuiLogBacklogSize),
+		}
+		m.order = append(m.order, name)
+	}
+	sort.Strings(m.order)
+	if m.focus == "" && len(m.order) > 0 {
+		m.focus = m.order[0]
+	}
+}
+
+func (m *tuiModel) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.filtering {
+		switch msg.Type {
+		case tea.KeyEsc:
+			m.filtering = false
+			m.filter = ""
+		case tea.KeyEnter:
+			m.filtering = false
+		case tea.KeyBackspace:
+			if len(m.filter) > 0 {
+				m.filter = m.filter[:len(m.filter)-1]
+			}
+		case tea.KeyRunes:
+			m.filter += string(msg.Runes)
+		}
+		return m, nil
+	}
+
+	switch msg.String() {
+	case "ctrl+c", "q":
+		m.quitting = true
+		return m, tea.Quit
+	case "j":
+		m.moveFocus(1)
+	case "k":
+		m.moveFocus(-1)
+	case "/":
+		m.filtering = true
+	}
+	return m, nil
+}
+
+func (m *tuiModel) visibleOrder() []string {
+	if m.filter == "" {
+		return m.order
+	}
+	var out []string
+	for _, name := range m.order {
+		if strings.Contains(name, m.filter) {
+			out = append(out, name)
+		}
+	}
+	return out
+}
+
+func (m *tuiModel) moveFocus(delta int) {
+	visible := m.visibleOrder()
+	if len(visible) == 0 {
+		return
+	}
+
+	idx := 0
+	for i, name := range visible {
+		if name == m.focus {
+			idx = i
+			break
+		}
+	}
+	idx = (idx + delta + len(visible)) % len(visible)
+	m.focus = visible[idx]
+}
+
+func (m *tuiModel) View() string {
+	if m.quitting {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString(tuiHeaderStyle.Render(fmt.Sprintf("%-40s %-10s %-10s %s", "PACKAGE", "VERSION", "STATE", "ELAPSED")))
+	b.WriteString("\n")
+	for _, name := range m.visibleOrder() {
+		row := m.rows[name]
+		line := fmt.Sprintf("%-40s %-10s %-10s %s", row.name, row.version, row.state, row.elapsed())
+		if name == m.focus {
+			line = tuiFocusStyle.Render(line)
+		} else {
+			line = m.styleForState(row.state).Render(line)
+		}
+		b.WriteString(line)
+		b.WriteString("\n")
+	}
+
+	if m.filtering || m.filter != "" {
+		fmt.Fprintf(&b, "\n/%s\n", m.filter)
+	}
+
+	b.WriteString("\n")
+	if row, ok := m.rows[m.focus]; ok {
+		tail := row.logs.tail(m.logPaneHeight())
+		pane := tuiLogPaneStyle
+		if m.width > 4 {
+			pane = pane.Width(m.width - 4)
+		}
+		b.WriteString(pane.Render(strings.Join(tail, "\n")))
+	}
+
+	return b.String()
+}
+
+func (m *tuiModel) styleForState(s tuiPackageState) lipgloss.Style {
+	switch s {
+	case tuiStateCached:
+		return tuiCachedStyle
+	case tuiStateBuilding:
+		return tuiBuildingStyle
+	case tuiStateDone:
+		return tuiDoneStyle
+	case tuiStateFailed:
+		return tuiFailedStyle
+	default:
+		return lipgloss.NewStyle()
+	}
+}
+
+func (m *tuiModel) logPaneHeight() int {
+	h := m.height - len(m.order) - 6
+	if h < 5 {
+		h = 5
+	}
+	return h
+}
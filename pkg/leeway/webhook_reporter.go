@@ -0,0 +1,262 @@
+package leeway
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// webhookBatchMaxBytes bounds how large a single batch of coalesced
+	// events is allowed to grow before it's flushed to the webhook.
+	webhookBatchMaxBytes = 64 * 1024
+
+	// webhookBatchMaxAge bounds how long events sit in a batch before it's
+	// flushed, even if webhookBatchMaxBytes hasn't been reached yet.
+	webhookBatchMaxAge = 250 * time.Millisecond
+
+	// webhookQueueSize bounds the number of pending batches kept in memory
+	// while the webhook endpoint is unreachable. Once full, new batches are
+	// dropped - the "do not block" contract on Reporter takes priority over
+	// delivering every single event to the dashboard.
+	webhookQueueSize = 64
+
+	webhookMaxRetries  = 5
+	webhookInitialWait = 500 * time.Millisecond
+	webhookMaxWait     = 30 * time.Second
+)
+
+// WebhookReporter posts build events as JSON to a remote coordinator, e.g. a
+// dashboard aggregating progress across a fleet of leeway workers. It is
+// configured via `--reporter=webhook --webhook-url=...`.
+//
+// High-frequency PackageBuildLog calls are coalesced into batches, bounded
+// by size and time, so that a chatty build command doesn't turn into one
+// HTTP request per log line. Batches that fail to deliver are retried with
+// exponential backoff; if the queue of undelivered batches fills up, further
+// batches are dropped rather than blocking the build - an unreachable
+// dashboard must never stall a build.
+type WebhookReporter struct {
+	url    string
+	token  string
+	client *http.Client
+
+	pending chan []jsonEvent
+	wg      sync.WaitGroup
+
+	mu    sync.Mutex
+	batch []jsonEvent
+	size  int
+	timer *time.Timer
+}
+
+// NewWebhookReporter produces a WebhookReporter that posts events to url,
+// authenticating with an optional bearer token (pass "" to disable auth).
+func NewWebhookReporter(url, token string) *WebhookReporter {
+	r := &WebhookReporter{
+		url:     url,
+		token:   token,
+		client:  &http.Client{Timeout: 10 * time.Second},
+		pending: make(chan []jsonEvent, webhookQueueSize),
+	}
+
+	go r.deliver()
+
+	return r
+}
+
+// deliver runs on its own goroutine for the lifetime of the reporter,
+// posting queued batches one at a time so that a slow or failing endpoint
+// only ever backs up the bounded queue, never the build hotpath.
+func (r *WebhookReporter) deliver() {
+	for batch := range r.pending {
+		r.post(batch)
+		r.wg.Done()
+	}
+}
+
+func (r *WebhookReporter) post(batch []jsonEvent) {
+	if len(batch) == 0 {
+		return
+	}
+
+	body, err := json.Marshal(batch)
+	if err != nil {
+		log.WithError(err).Error("webhook reporter: failed to marshal batch")
+		return
+	}
+
+	wait := webhookInitialWait
+	for attempt := 0; attempt <= webhookMaxRetries; attempt++ {
+		if attempt > 0 {
+			time.Sleep(wait)
+			wait *= 2
+			if wait > webhookMaxWait {
+				wait = webhookMaxWait
+			}
+		}
+
+		req, err := http.NewRequest(http.MethodPost, r.url, bytes.NewReader(body))
+		if err != nil {
+			log.WithError(err).Error("webhook reporter: failed to build request")
+			return
+		}
+		req.Header.Set("Content-Type", "application/json")
+		if r.token != "" {
+			req.Header.Set("Authorization", "Bearer "+r.token)
+		}
+
+		resp, err := r.client.Do(req)
+		if err != nil {
+			log.WithError(err).WithField("attempt", attempt).Warn("webhook reporter: delivery failed, will retry")
+			continue
+		}
+		resp.Body.Close()
+
+		if resp.StatusCode < 500 {
+			return
+		}
+		log.WithField("status", resp.StatusCode).WithField("attempt", attempt).Warn("webhook reporter: server error, will retry")
+	}
+
+	log.WithField("events", len(batch)).Error("webhook reporter: dropping batch after exhausting retries")
+}
+
+// emit adds evt to the batch currently being assembled, flushing it
+// immediately if either the size or age bound is reached.
+func (r *WebhookReporter) emit(evt jsonEvent) {
+	evt.SchemaVersion = jsonReporterSchemaVersion
+	evt.Time = time.Now()
+
+	buf, err := json.Marshal(evt)
+	if err != nil {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.batch == nil {
+		r.timer = time.AfterFunc(webhookBatchMaxAge, r.flush)
+	}
+	r.batch = append(r.batch, evt)
+	r.size += len(buf)
+
+	if r.size >= webhookBatchMaxBytes {
+		r.flushLocked()
+	}
+}
+
+// flush is invoked by the batch age timer.
+func (r *WebhookReporter) flush() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.flushLocked()
+}
+
+// flushLocked hands the current batch off to the delivery goroutine. Must
+// be called with r.mu held.
+func (r *WebhookReporter) flushLocked() {
+	if len(r.batch) == 0 {
+		return
+	}
+	if r.timer != nil {
+		r.timer.Stop()
+		r.timer = nil
+	}
+
+	batch := r.batch
+	r.batch = nil
+	r.size = 0
+
+	r.wg.Add(1)
+	select {
+	case r.pending <- batch:
+	default:
+		r.wg.Done()
+		log.WithField("events", len(batch)).Warn("webhook reporter: queue full, dropping batch")
+	}
+}
+
+// BuildStarted is called when the build of a package is started by the user.
+func (r *WebhookReporter) BuildStarted(pkg *Package, status map[*Package]PackageBuildStatus) {
+	version, err := pkg.Version()
+	if err != nil {
+		version = "unknown"
+	}
+	r.emit(jsonEvent{Type: jsonEventBuildStarted, Package: pkg.FullName(), Version: version})
+}
+
+// BuildFinished is called when the build of a package which was started by
+// the user has finished. It flushes and waits for every batch queued so far
+// to be delivered, so the process doesn't exit before the dashboard sees
+// the final events.
+func (r *WebhookReporter) BuildFinished(pkg *Package, err error) {
+	evt := jsonEvent{Type: jsonEventBuildFinished, Package: pkg.FullName()}
+	if err != nil {
+		evt.Error = err.Error()
+	}
+	r.emit(evt)
+
+	r.Drain(context.Background())
+}
+
+// PackageBuildStarted is called when a package build actually gets underway.
+func (r *WebhookReporter) PackageBuildStarted(pkg *Package) {
+	version, err := pkg.Version()
+	if err != nil {
+		version = "unknown"
+	}
+	r.emit(jsonEvent{Type: jsonEventPackageBuildStarted, Package: pkg.FullName(), Version: version})
+}
+
+// PackageBuildLog is called during a package build whenever a build command
+// produced some output. Log events are the ones most likely to arrive in a
+// tight loop, so they're the primary target of batch coalescing.
+func (r *WebhookReporter) PackageBuildLog(pkg *Package, isErr bool, buf []byte) {
+	stream := "stdout"
+	if isErr {
+		stream = "stderr"
+	}
+	r.emit(jsonEvent{
+		Type:    jsonEventPackageBuildLog,
+		Package: pkg.FullName(),
+		Stream:  stream,
+		Data:    base64.StdEncoding.EncodeToString(buf),
+	})
+}
+
+// PackageBuildFinished is called when the package build has finished.
+func (r *WebhookReporter) PackageBuildFinished(pkg *Package, err error) {
+	evt := jsonEvent{Type: jsonEventPackageBuildFinished, Package: pkg.FullName()}
+	if err != nil {
+		evt.Error = err.Error()
+	}
+	r.emit(evt)
+}
+
+// Drain flushes any batch still being assembled and blocks until the
+// delivery goroutine has worked through everything queued so far, or until
+// ctx is cancelled - whichever comes first.
+func (r *WebhookReporter) Drain(ctx context.Context) {
+	r.mu.Lock()
+	r.flushLocked()
+	r.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		r.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+	}
+}